@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mir
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/filecoin-project/mir/pkg/events"
+	eventpbtypes "github.com/filecoin-project/mir/pkg/pb/eventpb/types"
+)
+
+// Predicate decides whether a single Event is of interest to a subscriber
+// registered through Node.Subscribe. Predicates are typically written against
+// the generated eventpb/isspb wrapper types, e.g. to match a specific event
+// type (func(ev *eventpbtypes.Event) bool { _, ok := ev.Type.(*types.Event_SbDeliver); return ok })
+// or a specific destination module (func(ev *eventpbtypes.Event) bool { return ev.DestModule == moduleID }).
+type Predicate func(ev *eventpbtypes.Event) bool
+
+// CancelFunc unregisters a subscription created by Node.Subscribe.
+// Calling it more than once is a no-op.
+type CancelFunc func()
+
+// subscriberBufferSize is the capacity of each subscriber's output channel.
+// It bounds how far a subscriber can lag behind the event loop before
+// eventFeed starts dropping EventLists destined for it.
+const subscriberBufferSize = 64
+
+// subscription is a single subscriber registered with an eventFeed.
+type subscription struct {
+	filter  Predicate
+	eventsC chan *events.EventList
+	dropped uint64 // number of EventLists dropped because eventsC was full; accessed atomically
+}
+
+// Dropped returns the number of EventLists that could not be delivered to this
+// subscriber because its channel was full, and were discarded instead of
+// blocking the node's event loop.
+func (s *subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// eventFeed is an unbounded fan-out bus for the EventLists flowing through a
+// Node's event loop, modeled on go-ethereum's event.Feed: any number of
+// subscribers can attach typed filters, each with its own buffered channel,
+// so a slow or uninterested subscriber can never block delivery to the event
+// loop or to other subscribers.
+type eventFeed struct {
+	mu   sync.RWMutex
+	subs map[*subscription]struct{}
+}
+
+func newEventFeed() *eventFeed {
+	return &eventFeed{subs: make(map[*subscription]struct{})}
+}
+
+// subscribe registers a new subscription matching filter and returns the
+// channel on which matching events (wrapped in EventLists) will be delivered,
+// along with a CancelFunc to unregister it.
+func (f *eventFeed) subscribe(filter Predicate) (<-chan *events.EventList, CancelFunc) {
+	sub := &subscription{
+		filter:  filter,
+		eventsC: make(chan *events.EventList, subscriberBufferSize),
+	}
+
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			f.mu.Lock()
+			delete(f.subs, sub)
+			f.mu.Unlock()
+			close(sub.eventsC)
+		})
+	}
+
+	return sub.eventsC, cancel
+}
+
+// send filters el against every live subscription and delivers the matching
+// subset of events to each one. Delivery is non-blocking: if a subscriber's
+// buffer is full, the EventList is dropped for that subscriber and its
+// Dropped counter is incremented, rather than blocking the caller (and thus
+// the node's event loop).
+func (f *eventFeed) send(el *events.EventList) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for sub := range f.subs {
+		matched := events.EmptyList()
+		for _, ev := range el.Slice() {
+			if sub.filter(ev) {
+				matched.PushBack(ev)
+			}
+		}
+		if matched.Len() == 0 {
+			continue
+		}
+
+		select {
+		case sub.eventsC <- matched:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber to the stream of Events flowing through
+// this Node's event loop. filter is evaluated against every Event the Node's
+// Interceptor would also see; only Events matching it are delivered, wrapped
+// in EventLists, on the returned channel. Any number of subscribers may be
+// registered and canceled independently, and a slow subscriber only affects
+// its own channel: EventLists it cannot keep up with are dropped (see
+// CancelFunc and subscription.Dropped) rather than stalling the Node.
+func (n *Node) Subscribe(filter Predicate) (<-chan *events.EventList, CancelFunc) {
+	return n.eventFeed.subscribe(filter)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mir
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/mir/pkg/events"
+	eventpbtypes "github.com/filecoin-project/mir/pkg/pb/eventpb/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+)
+
+func listFor(destModules ...t.ModuleID) *events.EventList {
+	el := events.EmptyList()
+	for _, m := range destModules {
+		el.PushBack(&eventpbtypes.Event{DestModule: m})
+	}
+	return el
+}
+
+func destModuleFilter(m t.ModuleID) Predicate {
+	return func(ev *eventpbtypes.Event) bool {
+		return ev.DestModule == m
+	}
+}
+
+func TestEventFeedDeliversMatchingEvents(t2 *testing.T) {
+	f := newEventFeed()
+	eventsC, cancel := f.subscribe(destModuleFilter("a"))
+	defer cancel()
+
+	f.send(listFor("a", "b", "a"))
+
+	select {
+	case el := <-eventsC:
+		if el.Len() != 2 {
+			t2.Fatalf("expected 2 matching events, got %d", el.Len())
+		}
+	default:
+		t2.Fatal("expected a delivered EventList, got none")
+	}
+}
+
+func TestEventFeedSkipsNonMatchingSend(t2 *testing.T) {
+	f := newEventFeed()
+	eventsC, cancel := f.subscribe(destModuleFilter("a"))
+	defer cancel()
+
+	f.send(listFor("b"))
+
+	select {
+	case el := <-eventsC:
+		t2.Fatalf("expected no delivery, got EventList of length %d", el.Len())
+	default:
+	}
+}
+
+func TestEventFeedFansOutToMultipleSubscribers(t2 *testing.T) {
+	f := newEventFeed()
+	aC, cancelA := f.subscribe(destModuleFilter("a"))
+	defer cancelA()
+	bC, cancelB := f.subscribe(destModuleFilter("b"))
+	defer cancelB()
+
+	f.send(listFor("a", "b"))
+
+	select {
+	case el := <-aC:
+		if el.Len() != 1 {
+			t2.Fatalf("subscriber a: expected 1 event, got %d", el.Len())
+		}
+	default:
+		t2.Fatal("subscriber a: expected a delivered EventList, got none")
+	}
+
+	select {
+	case el := <-bC:
+		if el.Len() != 1 {
+			t2.Fatalf("subscriber b: expected 1 event, got %d", el.Len())
+		}
+	default:
+		t2.Fatal("subscriber b: expected a delivered EventList, got none")
+	}
+}
+
+func TestEventFeedCancelStopsDeliveryAndClosesChannel(t2 *testing.T) {
+	f := newEventFeed()
+	eventsC, cancel := f.subscribe(destModuleFilter("a"))
+
+	cancel()
+	cancel() // must be safe to call more than once
+
+	f.send(listFor("a"))
+
+	_, open := <-eventsC
+	if open {
+		t2.Fatal("expected channel to be closed after cancel")
+	}
+}
+
+func TestEventFeedDropsWhenSubscriberBufferFull(t2 *testing.T) {
+	f := newEventFeed()
+	sub, cancel := f.subscribe(destModuleFilter("a"))
+	defer cancel()
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		f.send(listFor("a"))
+	}
+
+	var s *subscription
+	for existing := range f.subs {
+		s = existing
+	}
+	if s == nil {
+		t2.Fatal("expected a registered subscription")
+	}
+	if s.Dropped() != 1 {
+		t2.Fatalf("expected exactly 1 dropped EventList, got %d", s.Dropped())
+	}
+
+	// Drain the EventLists that were buffered before the drop.
+	for i := 0; i < subscriberBufferSize; i++ {
+		<-sub
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mir
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/filecoin-project/mir/pkg/events"
+	"github.com/filecoin-project/mir/pkg/modules"
+	t "github.com/filecoin-project/mir/pkg/types"
+)
+
+// Interceptor is the interface implemented by debugging/diagnostic observers that
+// want to see every (stripped of follow-ups) EventList right before the Node's
+// event loop processes it, via n.interceptEvents.
+type Interceptor interface {
+	Intercept(events *events.EventList) error
+}
+
+// Node is a single node of the distributed system modeled and implemented by Mir.
+type Node struct {
+	ID t.NodeID
+
+	modules *modules.Modules
+
+	interceptor Interceptor
+
+	workChans       workChans
+	workErrNotifier *workErrNotifier
+	eventFeed       *eventFeed
+}
+
+// NewNode creates a new Mir Node with the given id and set of modules.
+func NewNode(id t.NodeID, nodeModules *modules.Modules, interceptor Interceptor) *Node {
+	werrNotifier := newWorkErrNotifier()
+
+	// Wrap every ActiveModule so a panic in its ApplyEvents fails the Node (via
+	// werrNotifier) like any other module error, instead of crashing the whole
+	// process. The wrappers replace the modules in nodeModules in place, under the
+	// same module IDs, so the work channels newWorkChans creates per module ID
+	// serve the wrapped modules.
+	superviseActiveModules(nodeModules, werrNotifier)
+
+	return &Node{
+		ID:              id,
+		modules:         nodeModules,
+		interceptor:     interceptor,
+		workChans:       newWorkChans(nodeModules),
+		workErrNotifier: werrNotifier,
+		eventFeed:       newEventFeed(),
+	}
+}
+
+// supervisedActiveConfig is the supervision policy applied to every ActiveModule run by a
+// Node. Restart is intentionally disabled: a Node is handed already-constructed module
+// instances, with no constructor to call to produce a fresh replacement, so all
+// supervision can safely provide here is panic containment.
+var supervisedActiveConfig = modules.SupervisedActiveConfig{
+	Restartable: false,
+}
+
+// superviseActiveModules replaces every ActiveModule in nodeModules.GenericModules with a
+// modules.SupervisedActive wrapping it, so that a panic in the module's ApplyEvents no
+// longer propagates out of processModuleEvents and crashes the Node, but instead fails
+// the Node's event loop through werrNotifier, exactly like a returned error would.
+func superviseActiveModules(nodeModules *modules.Modules, werrNotifier *workErrNotifier) {
+	for moduleID, module := range nodeModules.GenericModules {
+		active, ok := module.(modules.ActiveModule)
+		if !ok {
+			continue
+		}
+
+		supervised, err := modules.NewSupervisedActive(
+			func() (modules.ActiveModule, error) { return active, nil },
+			supervisedActiveConfig,
+			werrNotifier.Fail,
+		)
+		if err != nil {
+			// newInstance above never errors, so NewSupervisedActive cannot either.
+			panic(fmt.Errorf("unexpected error constructing SupervisedActive for module %v: %w", moduleID, err))
+		}
+
+		nodeModules.GenericModules[moduleID] = supervised
+	}
+}
+
+// interceptEvents passes events to the configured Interceptor, if any, ignoring any error
+// it returns, since interception is for debugging / diagnostic purposes only.
+func (n *Node) interceptEvents(events *events.EventList) {
+	if n.interceptor == nil {
+		return
+	}
+	_ = n.interceptor.Intercept(events)
+}
+
+// workErrNotifier lets any of a Node's worker goroutines fail the whole event loop
+// exactly once, and lets the rest of the Node observe that it happened.
+type workErrNotifier struct {
+	mu    sync.Mutex
+	err   error
+	exitC chan struct{}
+}
+
+func newWorkErrNotifier() *workErrNotifier {
+	return &workErrNotifier{exitC: make(chan struct{})}
+}
+
+// Fail records err as the reason the Node's event loop is stopping and closes ExitC.
+// Only the first call has an effect; subsequent calls are no-ops.
+func (w *workErrNotifier) Fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+		close(w.exitC)
+	}
+}
+
+// Err returns the error passed to the first call to Fail, or nil if Fail has not been called.
+func (w *workErrNotifier) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// ExitC returns a channel that is closed once Fail has been called.
+func (w *workErrNotifier) ExitC() <-chan struct{} {
+	return w.exitC
+}
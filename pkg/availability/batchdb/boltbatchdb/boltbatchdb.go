@@ -0,0 +1,431 @@
+// Package boltbatchdb implements a persistent batch database module,
+// backing the batchdbpb DSL interface with an embedded BoltDB store
+// instead of the plain Go maps used by fakebatchdb.
+package boltbatchdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	"github.com/filecoin-project/mir/pkg/dsl"
+	"github.com/filecoin-project/mir/pkg/modules"
+	batchdbpbdsl "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/dsl"
+	batchdbpbtypes "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/types"
+	trantorpb "github.com/filecoin-project/mir/pkg/pb/trantorpb"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+)
+
+// Bucket names for the logical mappings persisted in the database file.
+var (
+	batchesBucket      = []byte("batches")      // BatchID -> (txIDs, metadata)
+	transactionsBucket = []byte("transactions") // TxID -> serialized Transaction
+	retentionBucket    = []byte("retention")    // BatchID -> RetentionIndex (secondary index, drives GC)
+	txRefsBucket       = []byte("txrefs")       // TxID -> set of BatchIDs still referencing it
+)
+
+// ModuleConfig sets the module ids. All replicas are expected to use identical module configurations.
+type ModuleConfig struct {
+	Self t.ModuleID // id of this module
+}
+
+// Options configures the durability/throughput trade-off of the underlying BoltDB file.
+type Options struct {
+
+	// Path is the location on disk of the BoltDB file backing this module.
+	Path string
+
+	// NoSync disables BoltDB's fsync on every commit, trading durability for throughput.
+	// When false (the default), every StoreBatch is fsync'd before the response is emitted.
+	NoSync bool
+}
+
+// batchRecord is what is stored under a batch's key in batchesBucket.
+type batchRecord struct {
+	TxIDs    []tt.TxID
+	Metadata []byte
+}
+
+// NewModule returns a new module for a persistent, BoltDB-backed batch database.
+// Unlike fakebatchdb, the data survives process restarts: a warm node can serve
+// LookupBatch requests for batches stored before a crash.
+func NewModule(mc ModuleConfig, opts Options) (modules.Module, error) {
+	db, err := openDB(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m := dsl.NewModule(mc.Self)
+
+	// On StoreBatch request, write the batch, its transactions, its retention index entry,
+	// and the reverse TxID -> BatchIDs index in a single BoltDB transaction, so a batch
+	// only ever becomes visible once all its transactions are.
+	batchdbpbdsl.UponStoreBatch(m, func(batchID msctypes.BatchID, txIDs []tt.TxID, txs []*trantorpbtypes.Transaction, metadata []byte, retentionIndex tt.RetentionIndex, origin *batchdbpbtypes.StoreBatchOrigin) error {
+		if err := storeBatch(db, batchID, txIDs, txs, metadata, retentionIndex); err != nil {
+			return err
+		}
+		batchdbpbdsl.BatchStored(m, origin.Module, origin)
+		return nil
+	})
+
+	// On LookupBatch request, read the batch record and resolve its transactions.
+	batchdbpbdsl.UponLookupBatch(m, func(batchID msctypes.BatchID, origin *batchdbpbtypes.LookupBatchOrigin) error {
+		found, txs, err := lookupBatch(db, batchID)
+		if err != nil {
+			return err
+		}
+		batchdbpbdsl.LookupBatchResponse(m, origin.Module, found, txs, origin)
+		return nil
+	})
+
+	// On LookupTransaction request, look up the single transaction directly in the
+	// transactions bucket, without touching the batch it was originally stored under.
+	batchdbpbdsl.UponLookupTransaction(m, func(txID tt.TxID, origin *batchdbpbtypes.LookupTransactionOrigin) error {
+		tx, found, err := lookupTransaction(db, txID)
+		if err != nil {
+			return err
+		}
+		batchdbpbdsl.LookupTransactionResponse(m, origin.Module, found, tx, origin)
+		return nil
+	})
+
+	// On LookupTransactions request, resolve all the given ids in a single read-only
+	// BoltDB transaction, so a caller checking N ids pays one round-trip.
+	batchdbpbdsl.UponLookupTransactions(m, func(txIDs []tt.TxID, origin *batchdbpbtypes.LookupTransactionsOrigin) error {
+		found, txs, err := lookupTransactionsOptional(db, txIDs)
+		if err != nil {
+			return err
+		}
+		batchdbpbdsl.LookupTransactionsResponse(m, origin.Module, found, txs, origin)
+		return nil
+	})
+
+	// On GarbageCollect request, remove every batch whose retention index is strictly below
+	// the supplied threshold, along with every transaction no longer referenced by a surviving batch.
+	batchdbpbdsl.UponGarbageCollect(m, func(retentionIndex tt.RetentionIndex) error {
+		return garbageCollect(db, retentionIndex)
+	})
+
+	return m, nil
+}
+
+// openDB opens (creating if necessary) the BoltDB file at opts.Path and ensures all the
+// buckets this module needs exist.
+func openDB(opts Options) (*bbolt.DB, error) {
+	db, err := bbolt.Open(opts.Path, 0600, &bbolt.Options{NoSync: opts.NoSync})
+	if err != nil {
+		return nil, fmt.Errorf("could not open batch database at %s: %w", opts.Path, err)
+	}
+	db.NoSync = opts.NoSync
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range [][]byte{batchesBucket, transactionsBucket, retentionBucket, txRefsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("could not initialize batch database buckets: %w", err)
+	}
+
+	return db, nil
+}
+
+// storeBatch persists a batch, its transactions, its retention index entry, and the
+// reverse TxID -> BatchIDs index in a single BoltDB transaction, so a batch only ever
+// becomes visible once all its transactions are.
+func storeBatch(
+	db *bbolt.DB,
+	batchID msctypes.BatchID,
+	txIDs []tt.TxID,
+	txs []*trantorpbtypes.Transaction,
+	metadata []byte,
+	retentionIndex tt.RetentionIndex,
+) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		txsBucket := tx.Bucket(transactionsBucket)
+		refsBucket := tx.Bucket(txRefsBucket)
+		for i, txID := range txIDs {
+			raw, err := proto.Marshal(txs[i].Pb())
+			if err != nil {
+				return fmt.Errorf("could not serialize transaction %v: %w", txID, err)
+			}
+			if err := txsBucket.Put(txKey(txID), raw); err != nil {
+				return err
+			}
+
+			refs, err := getTxRefs(refsBucket, txID)
+			if err != nil {
+				return err
+			}
+			refs[batchID] = struct{}{}
+			if err := putTxRefs(refsBucket, txID, refs); err != nil {
+				return err
+			}
+		}
+
+		raw, err := encodeBatchRecord(batchRecord{TxIDs: txIDs, Metadata: metadata})
+		if err != nil {
+			return fmt.Errorf("could not serialize batch %v: %w", batchID, err)
+		}
+		if err := tx.Bucket(batchesBucket).Put(batchKey(batchID), raw); err != nil {
+			return err
+		}
+
+		return tx.Bucket(retentionBucket).Put(batchKey(batchID), encodeRetentionIndex(retentionIndex))
+	})
+}
+
+// lookupBatch resolves a stored batch to its transactions, in storage order.
+func lookupBatch(db *bbolt.DB, batchID msctypes.BatchID) (found bool, txs []*trantorpbtypes.Transaction, err error) {
+	var record batchRecord
+
+	if err := db.View(func(tx *bbolt.Tx) error {
+		raw := tx.Bucket(batchesBucket).Get(batchKey(batchID))
+		if raw == nil {
+			return nil
+		}
+		found = true
+		rec, err := decodeBatchRecord(raw)
+		if err != nil {
+			return fmt.Errorf("could not deserialize batch %v: %w", batchID, err)
+		}
+		record = rec
+		return nil
+	}); err != nil {
+		return false, nil, err
+	}
+
+	if !found {
+		return false, nil, nil
+	}
+
+	txs, err = lookupTransactions(db, record.TxIDs)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, txs, nil
+}
+
+// garbageCollect removes every batch whose retention index is strictly below
+// retentionIndex, along with every transaction no longer referenced by a surviving batch.
+func garbageCollect(db *bbolt.DB, retentionIndex tt.RetentionIndex) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		batches := tx.Bucket(batchesBucket)
+		retention := tx.Bucket(retentionBucket)
+		refs := tx.Bucket(txRefsBucket)
+		txsBucket := tx.Bucket(transactionsBucket)
+
+		var prunedBatchIDs []msctypes.BatchID
+		cursor := retention.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			if decodeRetentionIndex(v) < retentionIndex {
+				prunedBatchIDs = append(prunedBatchIDs, msctypes.BatchID(k))
+			}
+		}
+
+		for _, batchID := range prunedBatchIDs {
+			raw := batches.Get(batchKey(batchID))
+			if raw == nil {
+				continue
+			}
+			record, err := decodeBatchRecord(raw)
+			if err != nil {
+				return fmt.Errorf("could not deserialize batch %v during garbage collection: %w", batchID, err)
+			}
+
+			if err := batches.Delete(batchKey(batchID)); err != nil {
+				return err
+			}
+			if err := retention.Delete(batchKey(batchID)); err != nil {
+				return err
+			}
+
+			for _, txID := range record.TxIDs {
+				txRefs, err := getTxRefs(refs, txID)
+				if err != nil {
+					return err
+				}
+				delete(txRefs, batchID)
+
+				if len(txRefs) == 0 {
+					if err := refs.Delete(txKey(txID)); err != nil {
+						return err
+					}
+					if err := txsBucket.Delete(txKey(txID)); err != nil {
+						return err
+					}
+					continue
+				}
+
+				if err := putTxRefs(refs, txID, txRefs); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// decodeTx deserializes the raw protobuf-encoded transaction stored under a transactions
+// bucket key. It is the single place the transactions bucket's on-disk format is decoded,
+// shared by lookupTransaction, lookupTransactions and lookupTransactionsOptional.
+func decodeTx(txID tt.TxID, raw []byte) (*trantorpbtypes.Transaction, error) {
+	var pbTx trantorpb.Transaction
+	if err := proto.Unmarshal(raw, &pbTx); err != nil {
+		return nil, fmt.Errorf("could not deserialize transaction %v: %w", txID, err)
+	}
+	return trantorpbtypes.TransactionFromPb(&pbTx), nil
+}
+
+// lookupTransaction resolves a single transaction ID to its stored transaction, if any.
+func lookupTransaction(db *bbolt.DB, txID tt.TxID) (*trantorpbtypes.Transaction, bool, error) {
+	var tx *trantorpbtypes.Transaction
+	var found bool
+
+	if err := db.View(func(boltTx *bbolt.Tx) error {
+		raw := boltTx.Bucket(transactionsBucket).Get(txKey(txID))
+		if raw == nil {
+			return nil
+		}
+		found = true
+		decoded, err := decodeTx(txID, raw)
+		if err != nil {
+			return err
+		}
+		tx = decoded
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+
+	return tx, found, nil
+}
+
+// lookupTransactions resolves a list of transaction IDs to their stored transactions,
+// in a single read-only BoltDB transaction. Every id must resolve; it is an error for
+// one not to, since this is used to resolve the transactions of a batch that is known
+// to have been stored (see storeBatch), where every id it lists must still be present.
+func lookupTransactions(db *bbolt.DB, txIDs []tt.TxID) ([]*trantorpbtypes.Transaction, error) {
+	txs := make([]*trantorpbtypes.Transaction, len(txIDs))
+
+	if err := db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(transactionsBucket)
+		for i, txID := range txIDs {
+			raw := bucket.Get(txKey(txID))
+			if raw == nil {
+				return fmt.Errorf("transaction %v referenced by batch not found", txID)
+			}
+			decoded, err := decodeTx(txID, raw)
+			if err != nil {
+				return err
+			}
+			txs[i] = decoded
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// lookupTransactionsOptional resolves a list of transaction IDs to their stored
+// transactions, in a single read-only BoltDB transaction, same as lookupTransactions,
+// except a missing id is reported via found[i] == false rather than treated as an error.
+// This is what backs LookupTransactions, where an unresolvable id is a normal outcome,
+// not a bookkeeping inconsistency.
+func lookupTransactionsOptional(db *bbolt.DB, txIDs []tt.TxID) (found []bool, txs []*trantorpbtypes.Transaction, err error) {
+	found = make([]bool, len(txIDs))
+	txs = make([]*trantorpbtypes.Transaction, len(txIDs))
+
+	if err := db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(transactionsBucket)
+		for i, txID := range txIDs {
+			raw := bucket.Get(txKey(txID))
+			if raw == nil {
+				continue
+			}
+			decoded, err := decodeTx(txID, raw)
+			if err != nil {
+				return err
+			}
+			txs[i] = decoded
+			found[i] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return found, txs, nil
+}
+
+func batchKey(id msctypes.BatchID) []byte {
+	return []byte(id)
+}
+
+func txKey(id tt.TxID) []byte {
+	return []byte(id)
+}
+
+func encodeBatchRecord(r batchRecord) ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func decodeBatchRecord(raw []byte) (batchRecord, error) {
+	var r batchRecord
+	err := json.Unmarshal(raw, &r)
+	return r, err
+}
+
+func encodeRetentionIndex(ri tt.RetentionIndex) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(ri))
+	return buf
+}
+
+func decodeRetentionIndex(raw []byte) tt.RetentionIndex {
+	return tt.RetentionIndex(binary.BigEndian.Uint64(raw))
+}
+
+// getTxRefs reads the set of batches still referencing txID from the reverse index.
+func getTxRefs(bucket *bbolt.Bucket, txID tt.TxID) (map[msctypes.BatchID]struct{}, error) {
+	raw := bucket.Get(txKey(txID))
+	if raw == nil {
+		return make(map[msctypes.BatchID]struct{}), nil
+	}
+
+	var ids []msctypes.BatchID
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return nil, fmt.Errorf("could not deserialize reverse index for transaction %v: %w", txID, err)
+	}
+
+	refs := make(map[msctypes.BatchID]struct{}, len(ids))
+	for _, id := range ids {
+		refs[id] = struct{}{}
+	}
+	return refs, nil
+}
+
+// putTxRefs persists the set of batches still referencing txID in the reverse index.
+func putTxRefs(bucket *bbolt.Bucket, txID tt.TxID, refs map[msctypes.BatchID]struct{}) error {
+	ids := make([]msctypes.BatchID, 0, len(refs))
+	for id := range refs {
+		ids = append(ids, id)
+	}
+
+	raw, err := json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("could not serialize reverse index for transaction %v: %w", txID, err)
+	}
+	return bucket.Put(txKey(txID), raw)
+}
@@ -0,0 +1,140 @@
+package boltbatchdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.etcd.io/bbolt"
+
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+)
+
+func TestEncodeDecodeBatchRecordRoundTrip(t *testing.T) {
+	want := batchRecord{TxIDs: []tt.TxID{"t1", "t2"}, Metadata: []byte("meta")}
+
+	raw, err := encodeBatchRecord(want)
+	if err != nil {
+		t.Fatalf("encodeBatchRecord failed: %v", err)
+	}
+
+	got, err := decodeBatchRecord(raw)
+	if err != nil {
+		t.Fatalf("decodeBatchRecord failed: %v", err)
+	}
+
+	if len(got.TxIDs) != len(want.TxIDs) || got.TxIDs[0] != want.TxIDs[0] || got.TxIDs[1] != want.TxIDs[1] {
+		t.Fatalf("TxIDs round-trip mismatch: got %v, want %v", got.TxIDs, want.TxIDs)
+	}
+	if string(got.Metadata) != string(want.Metadata) {
+		t.Fatalf("Metadata round-trip mismatch: got %q, want %q", got.Metadata, want.Metadata)
+	}
+}
+
+func TestEncodeDecodeRetentionIndexRoundTrip(t *testing.T) {
+	want := tt.RetentionIndex(42)
+	if got := decodeRetentionIndex(encodeRetentionIndex(want)); got != want {
+		t.Fatalf("RetentionIndex round-trip mismatch: got %d, want %d", got, want)
+	}
+}
+
+func TestGetPutTxRefsRoundTrip(t *testing.T) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "batchdb"), 0600, nil)
+	if err != nil {
+		t.Fatalf("could not open test database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucket(txRefsBucket)
+		if err != nil {
+			return err
+		}
+
+		refs, err := getTxRefs(bucket, "t1")
+		if err != nil {
+			return err
+		}
+		if len(refs) != 0 {
+			t.Fatalf("expected no refs for an unknown transaction, got %v", refs)
+		}
+
+		refs["b1"] = struct{}{}
+		refs["b2"] = struct{}{}
+		if err := putTxRefs(bucket, "t1", refs); err != nil {
+			return err
+		}
+
+		got, err := getTxRefs(bucket, "t1")
+		if err != nil {
+			return err
+		}
+		if _, ok := got["b1"]; !ok {
+			t.Fatal("expected b1 in round-tripped refs")
+		}
+		if _, ok := got["b2"]; !ok {
+			t.Fatal("expected b2 in round-tripped refs")
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected exactly 2 refs, got %d", len(got))
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("db.Update failed: %v", err)
+	}
+}
+
+func tx(id string) *trantorpbtypes.Transaction {
+	return &trantorpbtypes.Transaction{Data: []byte(id)}
+}
+
+// TestBatchSurvivesRestart confirms the headline claim of this module over fakebatchdb:
+// a batch stored before the database is closed can still be looked up, by batch id and
+// by individual transaction id, after reopening a fresh *bbolt.DB at the same path.
+func TestBatchSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batchdb")
+
+	db, err := openDB(Options{Path: path})
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+
+	txIDs := []tt.TxID{"t1", "t2"}
+	txs := []*trantorpbtypes.Transaction{tx("t1"), tx("t2")}
+	batchID := msctypes.BatchID("b1")
+
+	if err := storeBatch(db, batchID, txIDs, txs, []byte("meta"), 1); err != nil {
+		t.Fatalf("storeBatch failed: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("could not close database: %v", err)
+	}
+
+	reopened, err := openDB(Options{Path: path})
+	if err != nil {
+		t.Fatalf("could not reopen database: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	found, gotTxs, err := lookupBatch(reopened, batchID)
+	if err != nil {
+		t.Fatalf("lookupBatch failed after reopening: %v", err)
+	}
+	if !found {
+		t.Fatal("expected batch to still be found after reopening the database")
+	}
+	if len(gotTxs) != 2 || string(gotTxs[0].Data) != "t1" || string(gotTxs[1].Data) != "t2" {
+		t.Fatalf("unexpected transactions after reopening: %v", gotTxs)
+	}
+
+	gotTx, found, err := lookupTransaction(reopened, "t1")
+	if err != nil {
+		t.Fatalf("lookupTransaction failed after reopening: %v", err)
+	}
+	if !found || string(gotTx.Data) != "t1" {
+		t.Fatalf("expected transaction t1 to still resolve after reopening, got %v, found=%v", gotTx, found)
+	}
+}
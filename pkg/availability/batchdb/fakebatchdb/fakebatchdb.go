@@ -19,6 +19,14 @@ type ModuleConfig struct {
 type moduleState struct {
 	BatchStore       map[msctypes.BatchID]batchInfo
 	TransactionStore map[tt.TxID]*trantorpbtypes.Transaction
+
+	// RetentionIndex associates each stored batch with the retention index it was stored under,
+	// so GarbageCollect can find and remove everything below a given threshold.
+	RetentionIndex map[msctypes.BatchID]tt.RetentionIndex
+
+	// TxRefs tracks, for each transaction, the set of batches still referencing it,
+	// so a transaction shared by several batches is only removed once none of them survive.
+	TxRefs map[tt.TxID]map[msctypes.BatchID]struct{}
 }
 
 type batchInfo struct {
@@ -26,46 +34,134 @@ type batchInfo struct {
 	metadata []byte
 }
 
-// NewModule returns a new module for a fake batch database.
-// It stores all the data in memory in plain go maps.
-func NewModule(mc ModuleConfig) modules.Module {
-	m := dsl.NewModule(mc.Self)
-
-	state := moduleState{
+func newModuleState() *moduleState {
+	return &moduleState{
 		BatchStore:       make(map[msctypes.BatchID]batchInfo),
 		TransactionStore: make(map[tt.TxID]*trantorpbtypes.Transaction),
+		RetentionIndex:   make(map[msctypes.BatchID]tt.RetentionIndex),
+		TxRefs:           make(map[tt.TxID]map[msctypes.BatchID]struct{}),
 	}
+}
 
-	// On StoreBatch request, just store the data in the local memory.
-	batchdbpbdsl.UponStoreBatch(m, func(batchID msctypes.BatchID, txIDs []tt.TxID, txs []*trantorpbtypes.Transaction, metadata []byte, origin *batchdbpbtypes.StoreBatchOrigin) error {
-		state.BatchStore[batchID] = batchInfo{
-			txIDs:    txIDs,
-			metadata: metadata,
+// storeBatch records a batch and its transactions in state, under the given retention index.
+func storeBatch(
+	state *moduleState,
+	batchID msctypes.BatchID,
+	txIDs []tt.TxID,
+	txs []*trantorpbtypes.Transaction,
+	metadata []byte,
+	retentionIndex tt.RetentionIndex,
+) {
+	state.BatchStore[batchID] = batchInfo{
+		txIDs:    txIDs,
+		metadata: metadata,
+	}
+	state.RetentionIndex[batchID] = retentionIndex
+
+	for i, txID := range txIDs {
+		state.TransactionStore[txID] = txs[i]
+
+		if state.TxRefs[txID] == nil {
+			state.TxRefs[txID] = make(map[msctypes.BatchID]struct{})
 		}
+		state.TxRefs[txID][batchID] = struct{}{}
+	}
+}
+
+// lookupBatch resolves a stored batch to its transactions, in storage order.
+func lookupBatch(state *moduleState, batchID msctypes.BatchID) (found bool, txs []*trantorpbtypes.Transaction) {
+	info, found := state.BatchStore[batchID]
+	if !found {
+		return false, nil
+	}
 
-		for i, txID := range txIDs {
-			state.TransactionStore[txID] = txs[i]
+	txs = make([]*trantorpbtypes.Transaction, len(info.txIDs))
+	for i, txID := range info.txIDs {
+		txs[i] = state.TransactionStore[txID]
+	}
+	return true, txs
+}
+
+// lookupTransaction resolves a single transaction ID directly, without scanning the batch it belongs to.
+func lookupTransaction(state *moduleState, txID tt.TxID) (tx *trantorpbtypes.Transaction, found bool) {
+	tx, found = state.TransactionStore[txID]
+	return tx, found
+}
+
+// lookupTransactions resolves every given transaction ID in one pass.
+func lookupTransactions(state *moduleState, txIDs []tt.TxID) (found []bool, txs []*trantorpbtypes.Transaction) {
+	found = make([]bool, len(txIDs))
+	txs = make([]*trantorpbtypes.Transaction, len(txIDs))
+
+	for i, txID := range txIDs {
+		txs[i], found[i] = state.TransactionStore[txID]
+	}
+	return found, txs
+}
+
+// garbageCollect removes every batch whose retention index is strictly below retentionIndex,
+// along with every transaction no longer referenced by a surviving batch.
+func garbageCollect(state *moduleState, retentionIndex tt.RetentionIndex) {
+	for batchID, batchRetIdx := range state.RetentionIndex {
+		if batchRetIdx >= retentionIndex {
+			continue
+		}
+
+		info := state.BatchStore[batchID]
+		delete(state.BatchStore, batchID)
+		delete(state.RetentionIndex, batchID)
+
+		for _, txID := range info.txIDs {
+			delete(state.TxRefs[txID], batchID)
+			if len(state.TxRefs[txID]) == 0 {
+				delete(state.TxRefs, txID)
+				delete(state.TransactionStore, txID)
+			}
 		}
+	}
+}
+
+// NewModule returns a new module for a fake batch database.
+// It stores all the data in memory in plain go maps.
+func NewModule(mc ModuleConfig) modules.Module {
+	m := dsl.NewModule(mc.Self)
+
+	state := newModuleState()
 
+	// On StoreBatch request, just store the data in the local memory.
+	batchdbpbdsl.UponStoreBatch(m, func(batchID msctypes.BatchID, txIDs []tt.TxID, txs []*trantorpbtypes.Transaction, metadata []byte, retentionIndex tt.RetentionIndex, origin *batchdbpbtypes.StoreBatchOrigin) error {
+		storeBatch(state, batchID, txIDs, txs, metadata, retentionIndex)
 		batchdbpbdsl.BatchStored(m, origin.Module, origin)
 		return nil
 	})
 
 	// On LookupBatch request, just check the local map.
 	batchdbpbdsl.UponLookupBatch(m, func(batchID msctypes.BatchID, origin *batchdbpbtypes.LookupBatchOrigin) error {
+		found, txs := lookupBatch(state, batchID)
+		batchdbpbdsl.LookupBatchResponse(m, origin.Module, found, txs, origin)
+		return nil
+	})
 
-		info, found := state.BatchStore[batchID]
-		if !found {
-			batchdbpbdsl.LookupBatchResponse(m, origin.Module, false, nil, origin)
-			return nil
-		}
+	// On LookupTransaction request, check the local transaction map directly,
+	// without having to re-scan the batch it belongs to.
+	batchdbpbdsl.UponLookupTransaction(m, func(txID tt.TxID, origin *batchdbpbtypes.LookupTransactionOrigin) error {
+		tx, found := lookupTransaction(state, txID)
+		batchdbpbdsl.LookupTransactionResponse(m, origin.Module, found, tx, origin)
+		return nil
+	})
 
-		txs := make([]*trantorpbtypes.Transaction, len(info.txIDs))
-		for i, txID := range info.txIDs {
-			txs[i] = state.TransactionStore[txID]
-		}
+	// On LookupTransactions request, resolve all the given ids in one pass,
+	// so a caller checking N ids pays one round-trip through the event loop instead of N.
+	batchdbpbdsl.UponLookupTransactions(m, func(txIDs []tt.TxID, origin *batchdbpbtypes.LookupTransactionsOrigin) error {
+		found, txs := lookupTransactions(state, txIDs)
+		batchdbpbdsl.LookupTransactionsResponse(m, origin.Module, found, txs, origin)
+		return nil
+	})
 
-		batchdbpbdsl.LookupBatchResponse(m, origin.Module, true, txs, origin)
+	// On GarbageCollect request, remove every batch whose retention index is strictly below
+	// the supplied threshold, along with every transaction no longer referenced by a surviving batch.
+	batchdbpbdsl.UponGarbageCollect(m, func(retentionIndex tt.RetentionIndex) error {
+		garbageCollect(state, retentionIndex)
 		return nil
 	})
 
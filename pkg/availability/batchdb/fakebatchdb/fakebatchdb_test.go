@@ -0,0 +1,85 @@
+package fakebatchdb
+
+import (
+	"testing"
+
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+)
+
+// tx returns a distinct Transaction value to stand in for the one associated with id;
+// the tests below only care about identity and reference counting, not its contents.
+func tx(id tt.TxID) *trantorpbtypes.Transaction {
+	return &trantorpbtypes.Transaction{Data: []byte(id)}
+}
+
+func TestLookupBatchAndTransactions(t *testing.T) {
+	state := newModuleState()
+	storeBatch(state, msctypes.BatchID("b1"), []tt.TxID{"t1", "t2"}, []*trantorpbtypes.Transaction{tx("t1"), tx("t2")}, []byte("meta"), 1)
+
+	found, txs := lookupBatch(state, msctypes.BatchID("b1"))
+	if !found || len(txs) != 2 {
+		t.Fatalf("expected batch b1 found with 2 transactions, got found=%v txs=%v", found, txs)
+	}
+
+	if _, found := lookupTransaction(state, "t1"); !found {
+		t.Fatal("expected t1 to be found")
+	}
+	if _, found := lookupTransaction(state, "missing"); found {
+		t.Fatal("expected missing transaction to not be found")
+	}
+
+	found2, txs2 := lookupTransactions(state, []tt.TxID{"t1", "missing", "t2"})
+	for i, want := range []bool{true, false, true} {
+		if found2[i] != want {
+			t.Fatalf("lookupTransactions found[%d] = %v, want %v", i, found2[i], want)
+		}
+	}
+	if txs2[1] != nil {
+		t.Fatalf("expected nil transaction for missing id, got %v", txs2[1])
+	}
+}
+
+func TestGarbageCollectRemovesOnlyBatchesBelowThreshold(t *testing.T) {
+	state := newModuleState()
+	storeBatch(state, "old", []tt.TxID{"t1"}, []*trantorpbtypes.Transaction{tx("t1")}, nil, 1)
+	storeBatch(state, "new", []tt.TxID{"t2"}, []*trantorpbtypes.Transaction{tx("t2")}, nil, 5)
+
+	garbageCollect(state, 3)
+
+	if _, found := state.BatchStore["old"]; found {
+		t.Fatal("expected batch 'old' (retention index 1) to be collected at threshold 3")
+	}
+	if _, found := state.BatchStore["new"]; !found {
+		t.Fatal("expected batch 'new' (retention index 5) to survive threshold 3")
+	}
+	if _, found := state.TransactionStore["t1"]; found {
+		t.Fatal("expected transaction t1 to be removed along with its only referencing batch")
+	}
+	if _, found := state.TransactionStore["t2"]; !found {
+		t.Fatal("expected transaction t2 to survive")
+	}
+}
+
+func TestGarbageCollectKeepsTransactionSharedBySurvivingBatch(t *testing.T) {
+	state := newModuleState()
+	shared := tx("shared")
+	storeBatch(state, "old", []tt.TxID{"shared"}, []*trantorpbtypes.Transaction{shared}, nil, 1)
+	storeBatch(state, "new", []tt.TxID{"shared"}, []*trantorpbtypes.Transaction{shared}, nil, 5)
+
+	garbageCollect(state, 3)
+
+	if _, found := state.BatchStore["old"]; found {
+		t.Fatal("expected batch 'old' to be collected")
+	}
+	if _, found := state.BatchStore["new"]; !found {
+		t.Fatal("expected batch 'new' to survive")
+	}
+	if _, found := state.TransactionStore["shared"]; !found {
+		t.Fatal("expected shared transaction to survive because 'new' still references it")
+	}
+	if refs := state.TxRefs["shared"]; len(refs) != 1 {
+		t.Fatalf("expected exactly 1 remaining reference to 'shared', got %d", len(refs))
+	}
+}
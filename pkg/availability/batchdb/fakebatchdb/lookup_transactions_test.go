@@ -0,0 +1,33 @@
+package fakebatchdb
+
+import (
+	"testing"
+
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+)
+
+// TestLookupTransactionsPreservesRequestOrder checks that LookupTransactions
+// resolves every id against the single pass described in its doc comment,
+// rather than, say, only resolving ids that happen to come first or skipping
+// the rest once one is missing.
+func TestLookupTransactionsPreservesRequestOrder(t *testing.T) {
+	state := newModuleState()
+	storeBatch(state, "b1", []tt.TxID{"t1", "t2", "t3"},
+		[]*trantorpbtypes.Transaction{tx("t1"), tx("t2"), tx("t3")}, nil, 1)
+
+	found, txs := lookupTransactions(state, []tt.TxID{"t3", "missing", "t1"})
+
+	wantFound := []bool{true, false, true}
+	for i, want := range wantFound {
+		if found[i] != want {
+			t.Fatalf("found[%d] = %v, want %v", i, found[i], want)
+		}
+	}
+	if txs[0] != state.TransactionStore["t3"] || txs[2] != state.TransactionStore["t1"] {
+		t.Fatal("expected resolved transactions to line up with the requested id order")
+	}
+	if txs[1] != nil {
+		t.Fatalf("expected nil for missing id, got %v", txs[1])
+	}
+}
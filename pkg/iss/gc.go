@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"github.com/filecoin-project/mir/pkg/dsl"
+	batchdbpbdsl "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/dsl"
+	isspbdsl "github.com/filecoin-project/mir/pkg/pb/isspb/dsl"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+)
+
+// HookBatchDBGarbageCollection registers, on m, the reaction that asks the batch
+// database module batchDB to garbage collect every batch below the current stable
+// retention index each time m is pushed a checkpoint. This bounds the batch
+// database's disk growth over long runs, as the availability layer no longer needs
+// to keep around batches a lagging node could no longer catch up with anyway.
+//
+// This only takes effect once called: it has no effect by itself, and must be
+// called with the real ISS module as m, alongside its other UponXxx registrations,
+// by whatever constructs that module. The actual ISS module implementation is not
+// part of this package.
+func HookBatchDBGarbageCollection(m dsl.Module, batchDB t.ModuleID, stableRetentionIndex func() tt.RetentionIndex) {
+	isspbdsl.UponPushCheckpoint(m, func() error {
+		batchdbpbdsl.GarbageCollect(m, batchDB, stableRetentionIndex())
+		return nil
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/mir/pkg/events"
+)
+
+// ReplayPolicy decides what happens to the events that were being applied to a
+// SupervisedActive module's wrapped instance at the moment it panicked, once a
+// replacement instance has been (re)started in its place.
+type ReplayPolicy int
+
+const (
+	// DropInFlightEvents discards the events that were in flight when the panic occurred.
+	DropInFlightEvents ReplayPolicy = iota
+
+	// ReplayInFlightEvents resubmits the in-flight events to the replacement instance.
+	ReplayInFlightEvents
+)
+
+// RestartBackoff configures the exponential backoff applied between successive
+// restart attempts of a SupervisedActive module's wrapped instance.
+type RestartBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	MaxAttempts  int     // 0 means unlimited restart attempts.
+	Jitter       float64 // Fraction of the computed delay randomized, in [0, 1].
+}
+
+// delay returns the backoff delay to apply before restart attempt number attempt (0-indexed).
+func (b RestartBackoff) delay(attempt int) time.Duration {
+	d := float64(b.InitialDelay) * math.Pow(2, float64(attempt))
+	if b.MaxDelay > 0 && d > float64(b.MaxDelay) {
+		d = float64(b.MaxDelay)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (rand.Float64()*2 - 1)
+	}
+	if d < 0 {
+		d = 0
+	}
+	return time.Duration(d)
+}
+
+// SupervisedActiveConfig configures a SupervisedActive module.
+type SupervisedActiveConfig struct {
+
+	// Restartable, if true, makes SupervisedActive tear down and re-instantiate
+	// the wrapped module (by calling its constructor again) after a panic,
+	// instead of treating the panic as fatal.
+	Restartable bool
+
+	// Backoff configures the delay between successive restart attempts.
+	Backoff RestartBackoff
+
+	// Replay decides what happens to the events that were in flight at the moment
+	// of a panic, once a replacement instance is ready to accept events again.
+	Replay ReplayPolicy
+}
+
+// applyRequest is a single ApplyEvents call waiting to be applied, in order, by run.
+type applyRequest struct {
+	ctx      context.Context
+	eventsIn *events.EventList
+	result   chan error
+}
+
+// SupervisedActive wraps an ActiveModule so that a panic in its ApplyEvents method
+// no longer crashes the whole Node. processModuleEvents invokes an ActiveModule's
+// ApplyEvents un-recovered, on the assumption (documented there) that an active
+// module "runs its own goroutines"; SupervisedActive is what makes that assumption
+// hold in practice. All calls to ApplyEvents are funneled through a single run
+// goroutine, so the wrapped instance is still only ever driven by one goroutine at
+// a time, in submission order, exactly as an un-wrapped ActiveModule would be. On a
+// panic, that goroutine (and only that goroutine) replaces the instance according
+// to mc before resuming.
+type SupervisedActive struct {
+	mc          SupervisedActiveConfig
+	newInstance func() (ActiveModule, error)
+	reportErr   func(error)
+
+	requestC chan applyRequest
+	wg       sync.WaitGroup
+}
+
+// NewSupervisedActive returns a SupervisedActive wrapping the ActiveModule instance
+// produced by newInstance. reportErr is invoked if the wrapped instance panics and
+// either mc.Restartable is false or mc.Backoff.MaxAttempts is exceeded; callers are
+// expected to wire it to the same workErrNotifier used elsewhere in the Node, so a
+// non-restartable or exhausted module fails the event loop exactly as it did before
+// being wrapped.
+func NewSupervisedActive(
+	newInstance func() (ActiveModule, error),
+	mc SupervisedActiveConfig,
+	reportErr func(error),
+) (*SupervisedActive, error) {
+	instance, err := newInstance()
+	if err != nil {
+		return nil, fmt.Errorf("could not create supervised module instance: %w", err)
+	}
+
+	s := &SupervisedActive{
+		mc:          mc,
+		newInstance: newInstance,
+		reportErr:   reportErr,
+		requestC:    make(chan applyRequest),
+	}
+
+	s.wg.Add(1)
+	go s.run(instance)
+
+	return s, nil
+}
+
+// ApplyEvents hands eventsIn to the run goroutine and blocks until run is done with
+// it. It returns the wrapped instance's own error (possibly nil) if eventsIn was
+// applied, nil if eventsIn was dropped after a panic and a successful restart (per
+// mc.Replay), and an error describing the panic if run could not recover from it
+// (see run). This keeps
+// ApplyEvents calls single-threaded and in submission order against the wrapped
+// instance, and preserves the synchronous stop-the-loop-on-error contract
+// processModuleEvents relies on for every ActiveModule.
+func (s *SupervisedActive) ApplyEvents(ctx context.Context, eventsIn *events.EventList) error {
+	req := applyRequest{ctx: ctx, eventsIn: eventsIn, result: make(chan error, 1)}
+
+	select {
+	case s.requestC <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run is the single goroutine that ever calls instance.ApplyEvents. It owns instance
+// and, on a panic, replaces it (restarting with exponential backoff, and replaying or
+// dropping the in-flight request that caused the panic, per mc) before resuming reads
+// from requestC. If mc.Replay is ReplayInFlightEvents and the replayed request panics
+// the freshly-restarted instance too, that is handled exactly like the original panic:
+// another restart attempt is made (subject to the same backoff and MaxAttempts budget)
+// rather than giving up on the first replay failure.
+func (s *SupervisedActive) run(instance ActiveModule) {
+	defer s.wg.Done()
+
+	attempts := 0
+	for req := range s.requestC {
+		eventsIn := req.eventsIn
+
+		for {
+			err, panicked := applySafely(instance, req.ctx, eventsIn)
+			if !panicked {
+				req.result <- err
+				break
+			}
+
+			if !s.mc.Restartable {
+				s.reportErr(err)
+				req.result <- err
+				break
+			}
+
+			if s.mc.Backoff.MaxAttempts > 0 && attempts >= s.mc.Backoff.MaxAttempts {
+				giveUpErr := fmt.Errorf("giving up after %d restart attempts: %w", attempts, err)
+				s.reportErr(giveUpErr)
+				req.result <- giveUpErr
+				break
+			}
+
+			time.Sleep(s.mc.Backoff.delay(attempts))
+			attempts++
+
+			replacement, restartErr := s.newInstance()
+			if restartErr != nil {
+				wrapped := fmt.Errorf("could not restart module after panic (%v): %w", err, restartErr)
+				s.reportErr(wrapped)
+				req.result <- wrapped
+				break
+			}
+			instance = replacement
+
+			if s.mc.Replay != ReplayInFlightEvents {
+				req.result <- nil
+				break
+			}
+
+			// Retry the loop with the same events against the replacement instance.
+			// If it panics again, it is handled as a fresh panic above, restarting
+			// again rather than failing out after a single replay attempt.
+		}
+	}
+}
+
+// applySafely calls instance.ApplyEvents(ctx, eventsIn), recovering any panic.
+// panicked is true iff a panic was recovered, in which case err describes it;
+// otherwise err is exactly what ApplyEvents returned (possibly nil).
+func applySafely(instance ActiveModule, ctx context.Context, eventsIn *events.EventList) (err error, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			err = fmt.Errorf("active module panicked: %v\nStack trace:\n%s", r, string(debug.Stack()))
+		}
+	}()
+
+	return instance.ApplyEvents(ctx, eventsIn), false
+}
+
+// Wait blocks until the run goroutine owned by this SupervisedActive has returned.
+// Close must be called first to let Wait return.
+func (s *SupervisedActive) Wait() {
+	s.wg.Wait()
+}
+
+// Close stops the run goroutine. It must be called exactly once, after all calls to
+// ApplyEvents have returned, typically as part of Node shutdown.
+func (s *SupervisedActive) Close() {
+	close(s.requestC)
+}
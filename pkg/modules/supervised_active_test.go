@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/mir/pkg/events"
+)
+
+// countingActiveModule is an ActiveModule test double whose ApplyEvents either
+// returns a fixed error, panics (once, on every call, or while a shared budget
+// is positive), or succeeds, while recording how many times and how
+// concurrently it was invoked.
+type countingActiveModule struct {
+	applyErr    error
+	panicOnce   bool
+	alwaysPanic bool
+	panicBudget *int32 // shared across instances produced by the same newInstance closure
+	panicked    int32
+	calls       int32
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (m *countingActiveModule) ApplyEvents(ctx context.Context, eventsIn *events.EventList) error {
+	n := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&m.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&m.maxInFlight, max, n) {
+			break
+		}
+	}
+
+	atomic.AddInt32(&m.calls, 1)
+
+	if m.panicBudget != nil {
+		for {
+			cur := atomic.LoadInt32(m.panicBudget)
+			if cur <= 0 {
+				break
+			}
+			if atomic.CompareAndSwapInt32(m.panicBudget, cur, cur-1) {
+				panic("simulated panic")
+			}
+		}
+	}
+
+	if m.alwaysPanic {
+		panic("simulated panic")
+	}
+	if m.panicOnce && atomic.CompareAndSwapInt32(&m.panicked, 0, 1) {
+		panic("simulated panic")
+	}
+
+	return m.applyErr
+}
+
+func TestSupervisedActiveReturnsUnderlyingError(t *testing.T) {
+	wantErr := errors.New("boom")
+	instance := &countingActiveModule{applyErr: wantErr}
+
+	s, err := NewSupervisedActive(
+		func() (ActiveModule, error) { return instance, nil },
+		SupervisedActiveConfig{},
+		func(error) {},
+	)
+	if err != nil {
+		t.Fatalf("NewSupervisedActive failed: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.ApplyEvents(context.Background(), events.EmptyList()); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestSupervisedActiveSerializesCalls(t *testing.T) {
+	instance := &countingActiveModule{}
+
+	s, err := NewSupervisedActive(
+		func() (ActiveModule, error) { return instance, nil },
+		SupervisedActiveConfig{},
+		func(error) {},
+	)
+	if err != nil {
+		t.Fatalf("NewSupervisedActive failed: %v", err)
+	}
+	defer s.Close()
+
+	const n = 20
+	errC := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errC <- s.ApplyEvents(context.Background(), events.EmptyList())
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errC; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if atomic.LoadInt32(&instance.calls) != n {
+		t.Fatalf("expected %d calls, got %d", n, instance.calls)
+	}
+	if atomic.LoadInt32(&instance.maxInFlight) != 1 {
+		t.Fatalf("expected calls to be serialized (max in-flight 1), got %d", instance.maxInFlight)
+	}
+}
+
+func TestSupervisedActiveRestartsAfterPanic(t *testing.T) {
+	instance := &countingActiveModule{panicOnce: true}
+
+	var reported []error
+	s, err := NewSupervisedActive(
+		func() (ActiveModule, error) { return instance, nil },
+		SupervisedActiveConfig{Restartable: true, Replay: DropInFlightEvents},
+		func(e error) { reported = append(reported, e) },
+	)
+	if err != nil {
+		t.Fatalf("NewSupervisedActive failed: %v", err)
+	}
+	defer s.Close()
+
+	// The call that triggers the panic gets the events dropped, not replayed,
+	// so it should complete successfully once the replacement instance is up.
+	if err := s.ApplyEvents(context.Background(), events.EmptyList()); err != nil {
+		t.Fatalf("expected no error after restart with DropInFlightEvents, got %v", err)
+	}
+
+	if err := s.ApplyEvents(context.Background(), events.EmptyList()); err != nil {
+		t.Fatalf("unexpected error on subsequent call: %v", err)
+	}
+
+	if len(reported) != 0 {
+		t.Fatalf("expected no fatal errors reported, got %v", reported)
+	}
+}
+
+func TestSupervisedActiveGivesUpAfterMaxAttempts(t *testing.T) {
+	// Every instance this produces panics on every call, so each ApplyEvents
+	// call below forces a restart attempt; MaxAttempts: 1 means only the first
+	// one is allowed, and the second must be refused instead of attempted.
+	newAlwaysPanicking := func() (ActiveModule, error) {
+		return &countingActiveModule{alwaysPanic: true}, nil
+	}
+
+	var reported []error
+	s, err := NewSupervisedActive(
+		newAlwaysPanicking,
+		SupervisedActiveConfig{
+			Restartable: true,
+			Backoff:     RestartBackoff{MaxAttempts: 1},
+			Replay:      DropInFlightEvents,
+		},
+		func(e error) { reported = append(reported, e) },
+	)
+	if err != nil {
+		t.Fatalf("NewSupervisedActive failed: %v", err)
+	}
+	defer s.Close()
+
+	call := func() error {
+		done := make(chan error, 1)
+		go func() { done <- s.ApplyEvents(context.Background(), events.EmptyList()) }()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("ApplyEvents did not return in time")
+			return nil
+		}
+	}
+
+	// First panic: still within the restart budget, restart succeeds, events dropped.
+	if err := call(); err != nil {
+		t.Fatalf("expected first restart to succeed silently, got error %v", err)
+	}
+	if len(reported) != 0 {
+		t.Fatalf("expected no fatal errors after first restart, got %v", reported)
+	}
+
+	// Second panic: restart budget exhausted, must be reported and returned.
+	if err := call(); err == nil {
+		t.Fatal("expected an error once the restart budget is exhausted")
+	}
+	if len(reported) != 1 {
+		t.Fatalf("expected exactly 1 fatal error reported, got %v", reported)
+	}
+}
+
+func TestSupervisedActiveRetriesWhenReplayPanicsAgain(t *testing.T) {
+	// Every instance produced here shares panicBudget: it panics on ApplyEvents
+	// while the budget is positive, and succeeds once it reaches 0. With
+	// ReplayInFlightEvents, a replayed call that panics again on the freshly
+	// restarted instance must keep retrying (consuming the same restart budget
+	// as any other panic), not be treated as an unconditional fatal error.
+	budget := int32(2)
+	newInstance := func() (ActiveModule, error) {
+		return &countingActiveModule{panicBudget: &budget}, nil
+	}
+
+	var reported []error
+	s, err := NewSupervisedActive(
+		newInstance,
+		SupervisedActiveConfig{
+			Restartable: true,
+			Backoff:     RestartBackoff{MaxAttempts: 5},
+			Replay:      ReplayInFlightEvents,
+		},
+		func(e error) { reported = append(reported, e) },
+	)
+	if err != nil {
+		t.Fatalf("NewSupervisedActive failed: %v", err)
+	}
+	defer s.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- s.ApplyEvents(context.Background(), events.EmptyList()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected ApplyEvents to eventually succeed once the panic budget is exhausted, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ApplyEvents did not return in time")
+	}
+
+	if len(reported) != 0 {
+		t.Fatalf("expected no fatal errors reported while restart budget was not exceeded, got %v", reported)
+	}
+}
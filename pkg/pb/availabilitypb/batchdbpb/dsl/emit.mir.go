@@ -0,0 +1,62 @@
+// See upon.mir.go for the package doc comment: this file is hand-written for the same
+// reason (no .proto source for batchdbpb exists in this tree to run codegen against).
+
+package batchdbpbdsl
+
+import (
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	dsl "github.com/filecoin-project/mir/pkg/dsl"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+
+	events "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/events"
+	types "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/types"
+)
+
+// Module-specific dsl functions for emitting events.
+
+func StoreBatch(
+	m dsl.Module,
+	destModule t.ModuleID,
+	batchID msctypes.BatchID,
+	txIDs []tt.TxID,
+	txs []*trantorpbtypes.Transaction,
+	metadata []byte,
+	retentionIndex tt.RetentionIndex,
+	origin *types.StoreBatchOrigin,
+) {
+	dsl.EmitMirEvent(m, events.StoreBatch(destModule, batchID, txIDs, txs, metadata, retentionIndex, origin))
+}
+
+func BatchStored(m dsl.Module, destModule t.ModuleID, origin *types.StoreBatchOrigin) {
+	dsl.EmitMirEvent(m, events.BatchStored(destModule, origin))
+}
+
+func LookupBatch(m dsl.Module, destModule t.ModuleID, batchID msctypes.BatchID, origin *types.LookupBatchOrigin) {
+	dsl.EmitMirEvent(m, events.LookupBatch(destModule, batchID, origin))
+}
+
+func LookupBatchResponse(m dsl.Module, destModule t.ModuleID, found bool, txs []*trantorpbtypes.Transaction, origin *types.LookupBatchOrigin) {
+	dsl.EmitMirEvent(m, events.LookupBatchResponse(destModule, found, txs, origin))
+}
+
+func GarbageCollect(m dsl.Module, destModule t.ModuleID, retentionIndex tt.RetentionIndex) {
+	dsl.EmitMirEvent(m, events.GarbageCollect(destModule, retentionIndex))
+}
+
+func LookupTransaction(m dsl.Module, destModule t.ModuleID, txID tt.TxID, origin *types.LookupTransactionOrigin) {
+	dsl.EmitMirEvent(m, events.LookupTransaction(destModule, txID, origin))
+}
+
+func LookupTransactions(m dsl.Module, destModule t.ModuleID, txIDs []tt.TxID, origin *types.LookupTransactionsOrigin) {
+	dsl.EmitMirEvent(m, events.LookupTransactions(destModule, txIDs, origin))
+}
+
+func LookupTransactionResponse(m dsl.Module, destModule t.ModuleID, found bool, tx *trantorpbtypes.Transaction, origin *types.LookupTransactionOrigin) {
+	dsl.EmitMirEvent(m, events.LookupTransactionResponse(destModule, found, tx, origin))
+}
+
+func LookupTransactionsResponse(m dsl.Module, destModule t.ModuleID, found []bool, txs []*trantorpbtypes.Transaction, origin *types.LookupTransactionsOrigin) {
+	dsl.EmitMirEvent(m, events.LookupTransactionsResponse(destModule, found, txs, origin))
+}
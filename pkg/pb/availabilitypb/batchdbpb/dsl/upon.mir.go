@@ -0,0 +1,82 @@
+// Package batchdbpbdsl provides the dsl.Module helpers for emitting and handling
+// batchdbpb events. These are hand-written, modeled on the shape Mir codegen produces
+// for other modules' pb/*pb/dsl packages (e.g. isspb), since no .proto source for
+// batchdbpb exists in this tree to run codegen against.
+package batchdbpbdsl
+
+import (
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	dsl "github.com/filecoin-project/mir/pkg/dsl"
+	eventpbtypes "github.com/filecoin-project/mir/pkg/pb/eventpb/types"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+
+	types "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/types"
+)
+
+// Module-specific dsl functions for processing events.
+
+func UponEvent[W types.Event_TypeWrapper[Ev], Ev any](m dsl.Module, handler func(ev *Ev) error) {
+	dsl.UponMirEvent[*eventpbtypes.Event_BatchDb](m, func(ev *types.Event) error {
+		w, ok := ev.Type.(W)
+		if !ok {
+			return nil
+		}
+
+		return handler(w.Unwrap())
+	})
+}
+
+func UponStoreBatch(m dsl.Module, handler func(batchID msctypes.BatchID, txIDs []tt.TxID, txs []*trantorpbtypes.Transaction, metadata []byte, retentionIndex tt.RetentionIndex, origin *types.StoreBatchOrigin) error) {
+	UponEvent[*types.Event_StoreBatch](m, func(ev *types.StoreBatch) error {
+		return handler(ev.BatchId, ev.TxIds, ev.Txs, ev.Metadata, ev.RetentionIndex, ev.Origin)
+	})
+}
+
+func UponBatchStored(m dsl.Module, handler func(origin *types.StoreBatchOrigin) error) {
+	UponEvent[*types.Event_BatchStored](m, func(ev *types.BatchStored) error {
+		return handler(ev.Origin)
+	})
+}
+
+func UponLookupBatch(m dsl.Module, handler func(batchID msctypes.BatchID, origin *types.LookupBatchOrigin) error) {
+	UponEvent[*types.Event_LookupBatch](m, func(ev *types.LookupBatch) error {
+		return handler(ev.BatchId, ev.Origin)
+	})
+}
+
+func UponLookupBatchResponse(m dsl.Module, handler func(found bool, txs []*trantorpbtypes.Transaction, origin *types.LookupBatchOrigin) error) {
+	UponEvent[*types.Event_LookupBatchResponse](m, func(ev *types.LookupBatchResponse) error {
+		return handler(ev.Found, ev.Txs, ev.Origin)
+	})
+}
+
+func UponGarbageCollect(m dsl.Module, handler func(retentionIndex tt.RetentionIndex) error) {
+	UponEvent[*types.Event_GarbageCollect](m, func(ev *types.GarbageCollect) error {
+		return handler(ev.RetentionIndex)
+	})
+}
+
+func UponLookupTransaction(m dsl.Module, handler func(txID tt.TxID, origin *types.LookupTransactionOrigin) error) {
+	UponEvent[*types.Event_LookupTransaction](m, func(ev *types.LookupTransaction) error {
+		return handler(ev.TxId, ev.Origin)
+	})
+}
+
+func UponLookupTransactionResponse(m dsl.Module, handler func(found bool, tx *trantorpbtypes.Transaction, origin *types.LookupTransactionOrigin) error) {
+	UponEvent[*types.Event_LookupTransactionResponse](m, func(ev *types.LookupTransactionResponse) error {
+		return handler(ev.Found, ev.Tx, ev.Origin)
+	})
+}
+
+func UponLookupTransactions(m dsl.Module, handler func(txIDs []tt.TxID, origin *types.LookupTransactionsOrigin) error) {
+	UponEvent[*types.Event_LookupTransactions](m, func(ev *types.LookupTransactions) error {
+		return handler(ev.TxIds, ev.Origin)
+	})
+}
+
+func UponLookupTransactionsResponse(m dsl.Module, handler func(found []bool, txs []*trantorpbtypes.Transaction, origin *types.LookupTransactionsOrigin) error) {
+	UponEvent[*types.Event_LookupTransactionsResponse](m, func(ev *types.LookupTransactionsResponse) error {
+		return handler(ev.Found, ev.Txs, ev.Origin)
+	})
+}
@@ -0,0 +1,111 @@
+// Package batchdbpbevents constructs the eventpb-wrapped events for the batchdbpb module.
+// These are hand-written, modeled on the shape Mir codegen produces for other modules'
+// pb/*pb/events packages (e.g. isspb), since no .proto source for batchdbpb exists in
+// this tree to run codegen against.
+package batchdbpbevents
+
+import (
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	eventpbtypes "github.com/filecoin-project/mir/pkg/pb/eventpb/types"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+
+	types "github.com/filecoin-project/mir/pkg/pb/availabilitypb/batchdbpb/types"
+)
+
+func wrap(destModule t.ModuleID, ev types.Event_Type) *eventpbtypes.Event {
+	return &eventpbtypes.Event{
+		DestModule: destModule,
+		Type:       &eventpbtypes.Event_BatchDb{BatchDb: &types.Event{Type: ev}},
+	}
+}
+
+func StoreBatch(
+	destModule t.ModuleID,
+	batchID msctypes.BatchID,
+	txIDs []tt.TxID,
+	txs []*trantorpbtypes.Transaction,
+	metadata []byte,
+	retentionIndex tt.RetentionIndex,
+	origin *types.StoreBatchOrigin,
+) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_StoreBatch{StoreBatch: &types.StoreBatch{
+		BatchId:        batchID,
+		TxIds:          txIDs,
+		Txs:            txs,
+		Metadata:       metadata,
+		RetentionIndex: retentionIndex,
+		Origin:         origin,
+	}})
+}
+
+func BatchStored(destModule t.ModuleID, origin *types.StoreBatchOrigin) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_BatchStored{BatchStored: &types.BatchStored{Origin: origin}})
+}
+
+func LookupBatch(destModule t.ModuleID, batchID msctypes.BatchID, origin *types.LookupBatchOrigin) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupBatch{LookupBatch: &types.LookupBatch{
+		BatchId: batchID,
+		Origin:  origin,
+	}})
+}
+
+func LookupBatchResponse(
+	destModule t.ModuleID,
+	found bool,
+	txs []*trantorpbtypes.Transaction,
+	origin *types.LookupBatchOrigin,
+) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupBatchResponse{LookupBatchResponse: &types.LookupBatchResponse{
+		Found:  found,
+		Txs:    txs,
+		Origin: origin,
+	}})
+}
+
+func GarbageCollect(destModule t.ModuleID, retentionIndex tt.RetentionIndex) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_GarbageCollect{GarbageCollect: &types.GarbageCollect{
+		RetentionIndex: retentionIndex,
+	}})
+}
+
+func LookupTransaction(destModule t.ModuleID, txID tt.TxID, origin *types.LookupTransactionOrigin) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupTransaction{LookupTransaction: &types.LookupTransaction{
+		TxId:   txID,
+		Origin: origin,
+	}})
+}
+
+func LookupTransactions(destModule t.ModuleID, txIDs []tt.TxID, origin *types.LookupTransactionsOrigin) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupTransactions{LookupTransactions: &types.LookupTransactions{
+		TxIds:  txIDs,
+		Origin: origin,
+	}})
+}
+
+func LookupTransactionResponse(
+	destModule t.ModuleID,
+	found bool,
+	tx *trantorpbtypes.Transaction,
+	origin *types.LookupTransactionOrigin,
+) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupTransactionResponse{LookupTransactionResponse: &types.LookupTransactionResponse{
+		Found:  found,
+		Tx:     tx,
+		Origin: origin,
+	}})
+}
+
+func LookupTransactionsResponse(
+	destModule t.ModuleID,
+	found []bool,
+	txs []*trantorpbtypes.Transaction,
+	origin *types.LookupTransactionsOrigin,
+) *eventpbtypes.Event {
+	return wrap(destModule, &types.Event_LookupTransactionsResponse{LookupTransactionsResponse: &types.LookupTransactionsResponse{
+		Found:  found,
+		Txs:    txs,
+		Origin: origin,
+	}})
+}
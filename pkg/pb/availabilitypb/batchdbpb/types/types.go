@@ -0,0 +1,187 @@
+// Package batchdbpbtypes defines the Go-native mirrors of the batchdbpb event types.
+// These are hand-written, modeled on the shape Mir codegen produces for other modules'
+// pb/*pb/types packages (e.g. isspb), since no .proto source for batchdbpb exists in
+// this tree to run codegen against.
+package batchdbpbtypes
+
+import (
+	msctypes "github.com/filecoin-project/mir/pkg/availability/multisigcollector/types"
+	trantorpbtypes "github.com/filecoin-project/mir/pkg/pb/trantorpb/types"
+	tt "github.com/filecoin-project/mir/pkg/trantor/types"
+	t "github.com/filecoin-project/mir/pkg/types"
+)
+
+// Event_Type is implemented by every variant that can occur in Event.Type.
+type Event_Type interface {
+	isEvent_Type()
+}
+
+// Event is the batchdbpb module's own event, analogous to isspbtypes.Event:
+// it is carried, nested, inside the top-level eventpb.Event.
+type Event struct {
+	Type Event_Type
+}
+
+// Event_TypeWrapper lets dsl.UponMirEvent-style generic handlers recover the
+// concrete payload (Ev) of an Event.Type variant without a type switch at each call site.
+type Event_TypeWrapper[Ev any] interface {
+	Event_Type
+	Unwrap() *Ev
+}
+
+type StoreBatch struct {
+	BatchId        msctypes.BatchID
+	TxIds          []tt.TxID
+	Txs            []*trantorpbtypes.Transaction
+	Metadata       []byte
+	RetentionIndex tt.RetentionIndex
+	Origin         *StoreBatchOrigin
+}
+
+type Event_StoreBatch struct {
+	StoreBatch *StoreBatch
+}
+
+func (*Event_StoreBatch) isEvent_Type() {}
+func (w *Event_StoreBatch) Unwrap() *StoreBatch { return w.StoreBatch }
+
+type BatchStored struct {
+	Origin *StoreBatchOrigin
+}
+
+type Event_BatchStored struct {
+	BatchStored *BatchStored
+}
+
+func (*Event_BatchStored) isEvent_Type() {}
+func (w *Event_BatchStored) Unwrap() *BatchStored { return w.BatchStored }
+
+type LookupBatch struct {
+	BatchId msctypes.BatchID
+	Origin  *LookupBatchOrigin
+}
+
+type Event_LookupBatch struct {
+	LookupBatch *LookupBatch
+}
+
+func (*Event_LookupBatch) isEvent_Type() {}
+func (w *Event_LookupBatch) Unwrap() *LookupBatch { return w.LookupBatch }
+
+type LookupBatchResponse struct {
+	Found  bool
+	Txs    []*trantorpbtypes.Transaction
+	Origin *LookupBatchOrigin
+}
+
+type Event_LookupBatchResponse struct {
+	LookupBatchResponse *LookupBatchResponse
+}
+
+func (*Event_LookupBatchResponse) isEvent_Type() {}
+func (w *Event_LookupBatchResponse) Unwrap() *LookupBatchResponse {
+	return w.LookupBatchResponse
+}
+
+type GarbageCollect struct {
+	RetentionIndex tt.RetentionIndex
+}
+
+type Event_GarbageCollect struct {
+	GarbageCollect *GarbageCollect
+}
+
+func (*Event_GarbageCollect) isEvent_Type() {}
+func (w *Event_GarbageCollect) Unwrap() *GarbageCollect { return w.GarbageCollect }
+
+type LookupTransaction struct {
+	TxId   tt.TxID
+	Origin *LookupTransactionOrigin
+}
+
+type Event_LookupTransaction struct {
+	LookupTransaction *LookupTransaction
+}
+
+func (*Event_LookupTransaction) isEvent_Type() {}
+func (w *Event_LookupTransaction) Unwrap() *LookupTransaction {
+	return w.LookupTransaction
+}
+
+type LookupTransactionResponse struct {
+	Found  bool
+	Tx     *trantorpbtypes.Transaction
+	Origin *LookupTransactionOrigin
+}
+
+type Event_LookupTransactionResponse struct {
+	LookupTransactionResponse *LookupTransactionResponse
+}
+
+func (*Event_LookupTransactionResponse) isEvent_Type() {}
+func (w *Event_LookupTransactionResponse) Unwrap() *LookupTransactionResponse {
+	return w.LookupTransactionResponse
+}
+
+type LookupTransactions struct {
+	TxIds  []tt.TxID
+	Origin *LookupTransactionsOrigin
+}
+
+type Event_LookupTransactions struct {
+	LookupTransactions *LookupTransactions
+}
+
+func (*Event_LookupTransactions) isEvent_Type() {}
+func (w *Event_LookupTransactions) Unwrap() *LookupTransactions {
+	return w.LookupTransactions
+}
+
+// LookupTransactionsResponse carries, in Found and Txs, a same-order, same-length
+// bit-vector/slice pair answering a batched LookupTransactions request.
+type LookupTransactionsResponse struct {
+	Found  []bool
+	Txs    []*trantorpbtypes.Transaction
+	Origin *LookupTransactionsOrigin
+}
+
+type Event_LookupTransactionsResponse struct {
+	LookupTransactionsResponse *LookupTransactionsResponse
+}
+
+func (*Event_LookupTransactionsResponse) isEvent_Type() {}
+func (w *Event_LookupTransactionsResponse) Unwrap() *LookupTransactionsResponse {
+	return w.LookupTransactionsResponse
+}
+
+// StoreBatchOrigin identifies the module (and, via Type, the request) a BatchStored
+// response must be routed back to.
+type StoreBatchOrigin struct {
+	Module t.ModuleID
+	Type   OriginType
+}
+
+// LookupBatchOrigin identifies the module (and, via Type, the request) a
+// LookupBatchResponse must be routed back to.
+type LookupBatchOrigin struct {
+	Module t.ModuleID
+	Type   OriginType
+}
+
+// LookupTransactionOrigin identifies the module (and, via Type, the request) a
+// LookupTransactionResponse must be routed back to.
+type LookupTransactionOrigin struct {
+	Module t.ModuleID
+	Type   OriginType
+}
+
+// LookupTransactionsOrigin identifies the module (and, via Type, the request) a
+// LookupTransactionsResponse must be routed back to.
+type LookupTransactionsOrigin struct {
+	Module t.ModuleID
+	Type   OriginType
+}
+
+// OriginType is an opaque, caller-defined payload threaded through an Origin and
+// handed back unmodified to the handler of the corresponding response.
+type OriginType interface{}
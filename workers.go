@@ -112,6 +112,11 @@ func (n *Node) processEvents(
 	// This is only for debugging / diagnostic purposes.
 	n.interceptEvents(plainEvents)
 
+	// Fan the same (stripped of all follow-ups) events out to any Subscribe-rs.
+	// Subscribers thus see exactly the same stream as the Interceptor, without requiring
+	// one to patch the interceptor slot.
+	n.eventFeed.send(plainEvents)
+
 	// Process events.
 	newEvents, err := processFunc(ctx, plainEvents)
 	if err != nil {
@@ -180,6 +185,9 @@ func (n *Node) processModuleEvents(
 	// This is only for debugging / diagnostic purposes.
 	n.interceptEvents(plainEvents)
 
+	// Fan the same (stripped of all follow-ups) events out to any Subscribe-rs.
+	n.eventFeed.send(plainEvents)
+
 	// Process events.
 	switch m := module.(type) {
 
@@ -196,9 +204,10 @@ func (n *Node) processModuleEvents(
 
 	case modules.ActiveModule:
 		// For an active module, only submit the events to the module and let it output the result asynchronously.
-		// Note that, unlike with a PassiveModule, an ActiveModule's ApplyEvents method is not invoked "safely",
-		// i.e., a potential panic is not caught.
-		// This is because an ActiveModule is expected to run its own goroutines.
+		// Unlike a PassiveModule, m.ApplyEvents is not wrapped in a recover() here. Instead, NewNode wraps every
+		// ActiveModule in a modules.SupervisedActive, which recovers panics raised by ApplyEvents and reports them
+		// through n.workErrNotifier, same as a returned error. Panics in goroutines that the module starts
+		// itself are not recovered.
 
 		if err := m.ApplyEvents(ctx, plainEvents); err != nil {
 			return err, false